@@ -0,0 +1,78 @@
+package globaldns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudflare/cloudflare-go"
+	"github.com/rancher/types/apis/management.cattle.io/v3"
+)
+
+// cloudflarePublisher publishes GlobalDNS endpoints directly to a CloudFlare DNS zone via
+// the CloudFlare API, bypassing the dummy-ingress/external-dns shim.
+type cloudflarePublisher struct {
+	controller *GDController
+	globaldns  *v3.GlobalDNS
+}
+
+func newCloudflarePublisher(controller *GDController, globaldns *v3.GlobalDNS) Publisher {
+	return &cloudflarePublisher{controller: controller, globaldns: globaldns}
+}
+
+// Reconcile ignores endpoints' per-cluster weights: CloudFlare's free/pro API has no
+// weighted-routing concept, so every reachable endpoint is published as its own record,
+// typed A or CNAME per endpoint (hostname endpoints, e.g. classic ELB/ALB, need CNAME).
+func (p *cloudflarePublisher) Reconcile(ctx context.Context, fqdn string, endpoints []weightedEndpoint, ttl int) error {
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	creds, err := p.controller.dnsProviderCredentials(p.globaldns, "apiEmail", "apiKey", "zoneID")
+	if err != nil {
+		return fmt.Errorf("cloudflare: %v", err)
+	}
+	apiEmail, apiKey, zoneID := creds[0], creds[1], creds[2]
+
+	api, err := cloudflare.New(apiKey, apiEmail)
+	if err != nil {
+		return fmt.Errorf("cloudflare: error creating API client: %v", err)
+	}
+
+	existing, err := api.DNSRecords(ctx, zoneID, cloudflare.DNSRecord{Name: fqdn})
+	if err != nil {
+		return fmt.Errorf("cloudflare: error listing records for %v: %v", fqdn, err)
+	}
+
+	// CloudFlare has no upsert call, so publish one record per endpoint: keep the records
+	// that already match an endpoint, delete the other records under fqdn and create the rest.
+	remaining := map[string]string{}
+	for _, ep := range endpoints {
+		recordType := "A"
+		if isHostnameEndpoint(ep.Endpoint) {
+			recordType = "CNAME"
+		}
+		remaining[ep.Endpoint] = recordType
+	}
+	for _, rec := range existing {
+		if remaining[rec.Content] == rec.Type {
+			delete(remaining, rec.Content)
+			continue
+		}
+		if err := api.DeleteDNSRecord(ctx, zoneID, rec.ID); err != nil {
+			return fmt.Errorf("cloudflare: error deleting stale record %v for %v: %v", rec.Content, fqdn, err)
+		}
+	}
+
+	for ep, recordType := range remaining {
+		_, err := api.CreateDNSRecord(ctx, zoneID, cloudflare.DNSRecord{
+			Type:    recordType,
+			Name:    fqdn,
+			Content: ep,
+			TTL:     ttl,
+		})
+		if err != nil {
+			return fmt.Errorf("cloudflare: error creating record %v for %v: %v", ep, fqdn, err)
+		}
+	}
+	return nil
+}
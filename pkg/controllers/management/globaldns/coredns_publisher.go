@@ -0,0 +1,99 @@
+package globaldns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/rancher/types/apis/management.cattle.io/v3"
+	clientv3 "go.etcd.io/etcd/clientv3"
+)
+
+// etcdSkydnsPrefix is the default key prefix the CoreDNS etcd plugin reads records from.
+const etcdSkydnsPrefix = "/skydns"
+
+// corednsRecord mirrors the JSON value format the CoreDNS etcd plugin expects under a
+// /skydns/... key: a host (IP or CNAME target) with an optional TTL and weight.
+type corednsRecord struct {
+	Host   string `json:"host"`
+	TTL    int    `json:"ttl,omitempty"`
+	Weight int64  `json:"weight,omitempty"`
+}
+
+// coreDNSPublisher publishes GlobalDNS endpoints straight into the etcd keyspace a CoreDNS
+// deployment's etcd plugin watches, for air-gapped clusters that have no route to an
+// external DNS provider at all.
+type coreDNSPublisher struct {
+	controller *GDController
+	globaldns  *v3.GlobalDNS
+}
+
+func newCoreDNSPublisher(controller *GDController, globaldns *v3.GlobalDNS) Publisher {
+	return &coreDNSPublisher{controller: controller, globaldns: globaldns}
+}
+
+func (p *coreDNSPublisher) Reconcile(ctx context.Context, fqdn string, endpoints []weightedEndpoint, ttl int) error {
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	creds, err := p.controller.dnsProviderCredentials(p.globaldns, "endpoints")
+	if err != nil {
+		return fmt.Errorf("coredns: %v", err)
+	}
+
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   strings.Split(creds[0], ","),
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("coredns: error connecting to etcd: %v", err)
+	}
+	defer cli.Close()
+
+	prefix := skydnsKey(fqdn)
+	existing, err := cli.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return fmt.Errorf("coredns: error listing records under %v: %v", prefix, err)
+	}
+	for _, kv := range existing.Kvs {
+		if _, err := cli.Delete(ctx, string(kv.Key)); err != nil {
+			return fmt.Errorf("coredns: error deleting stale record %v: %v", string(kv.Key), err)
+		}
+	}
+
+	for i, ep := range endpoints {
+		record := corednsRecord{Host: ep.Endpoint, TTL: ttl, Weight: ep.Weight}
+		value, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("coredns: error marshalling record for %v: %v", ep.Endpoint, err)
+		}
+		key := fmt.Sprintf("%v/%v", prefix, recordLabel(i, ep.Endpoint))
+		if _, err := cli.Put(ctx, key, string(value)); err != nil {
+			return fmt.Errorf("coredns: error writing record %v: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// skydnsKey turns a FQDN into the reversed-label etcd path the CoreDNS etcd plugin expects,
+// e.g. "www.example.com" -> "/skydns/com/example/www".
+func skydnsKey(fqdn string) string {
+	labels := strings.Split(strings.TrimSuffix(fqdn, "."), ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	return etcdSkydnsPrefix + "/" + strings.Join(labels, "/")
+}
+
+// recordLabel gives each endpoint under the same FQDN a distinct leaf key: the IP itself
+// (with ':' replaced by '-') for IP endpoints, or "ep-<position in endpoints>" for hostnames.
+func recordLabel(i int, endpoint string) string {
+	if net.ParseIP(endpoint) != nil {
+		return strings.ReplaceAll(endpoint, ":", "-")
+	}
+	return fmt.Sprintf("ep-%d", i)
+}
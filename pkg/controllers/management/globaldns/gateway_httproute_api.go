@@ -0,0 +1,164 @@
+package globaldns
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/types/config"
+	apiv1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gatewayclientv1beta1 "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/typed/apis/v1beta1"
+)
+
+// defaultGatewayParentName is the Gateway every globaldns-ingress-<name> HTTPRoute is
+// parented to. This controller does not create that Gateway: it has to exist already, the
+// same way an ingress controller has to exist when using the Ingress-backed publishing path.
+const defaultGatewayParentName = "rancher-gateway"
+
+// gatewayHTTPRouteAPI implements ingressAPI against the Gateway API's HTTPRoute resource, for
+// clusters that have moved off Ingress entirely. It materializes an HTTPRoute, parented to
+// defaultGatewayParentName, with a placeholder BackendRef mirroring the http-svc-dummy
+// pattern the Ingress path uses.
+//
+// Unlike an Ingress, an HTTPRoute itself carries no resolved address: a route's per-parent
+// status (RouteParentStatus) only has ParentRef/ControllerName/Conditions. The address lives
+// on the parent Gateway's own Status.Addresses, so reading endpoints back means fetching
+// that Gateway, not the route. UpdateStatus is a no-op beyond returning the current state -
+// there is no analogous "push a status" operation for this backend, since both the route's
+// Accepted condition and the Gateway's addresses are written by the Gateway controller.
+type gatewayHTTPRouteAPI struct {
+	httpRoutes gatewayclientv1beta1.HTTPRouteInterface
+	gateways   gatewayclientv1beta1.GatewayInterface
+}
+
+func newGatewayHTTPRouteAPI(mgmt *config.ManagementContext) ingressAPI {
+	return &gatewayHTTPRouteAPI{
+		httpRoutes: mgmt.GatewayClient.GatewayV1beta1().HTTPRoutes(namespace.GlobalNamespace),
+		gateways:   mgmt.GatewayClient.GatewayV1beta1().Gateways(namespace.GlobalNamespace),
+	}
+}
+
+func (a *gatewayHTTPRouteAPI) Get(namespace, name string) (*ingressSpec, error) {
+	route, err := a.httpRoutes.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return a.specFromHTTPRoute(route)
+}
+
+func (a *gatewayHTTPRouteAPI) Create(spec *ingressSpec) (*ingressSpec, error) {
+	created, err := a.httpRoutes.Create(context.TODO(), httpRouteFromSpec(spec), metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return a.specFromHTTPRoute(created)
+}
+
+func (a *gatewayHTTPRouteAPI) Update(spec *ingressSpec) (*ingressSpec, error) {
+	route, err := a.httpRoutes.Get(context.TODO(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	route.Annotations = spec.Annotations
+	route.OwnerReferences = spec.OwnerReferences
+	route.Spec.Hostnames = []gatewayv1beta1.Hostname{gatewayv1beta1.Hostname(spec.Host)}
+	updated, err := a.httpRoutes.Update(context.TODO(), route, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return a.specFromHTTPRoute(updated)
+}
+
+// UpdateStatus is a no-op for HTTPRoute: the addresses callers want published live on the
+// parent Gateway's status, which only the Gateway controller writes, so there's nothing for
+// us to push. It still returns the route's current state (including whatever addresses the
+// Gateway controller has assigned the parent Gateway so far) so callers can log and compare
+// against it like any other ingressAPI.
+func (a *gatewayHTTPRouteAPI) UpdateStatus(spec *ingressSpec) (*ingressSpec, error) {
+	return a.Get(spec.Namespace, spec.Name)
+}
+
+func httpRouteFromSpec(spec *ingressSpec) *gatewayv1beta1.HTTPRoute {
+	pathType := gatewayv1beta1.PathMatchPathPrefix
+	path := "/"
+	port := gatewayv1beta1.PortNumber(42)
+	return &gatewayv1beta1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            spec.Name,
+			Namespace:       spec.Namespace,
+			OwnerReferences: spec.OwnerReferences,
+			Annotations:     spec.Annotations,
+		},
+		Spec: gatewayv1beta1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1beta1.CommonRouteSpec{
+				ParentRefs: []gatewayv1beta1.ParentReference{
+					{Name: gatewayv1beta1.ObjectName(defaultGatewayParentName)},
+				},
+			},
+			Hostnames: []gatewayv1beta1.Hostname{gatewayv1beta1.Hostname(spec.Host)},
+			Rules: []gatewayv1beta1.HTTPRouteRule{
+				{
+					Matches: []gatewayv1beta1.HTTPRouteMatch{
+						{Path: &gatewayv1beta1.HTTPPathMatch{Type: &pathType, Value: &path}},
+					},
+					BackendRefs: []gatewayv1beta1.HTTPBackendRef{
+						{
+							BackendRef: gatewayv1beta1.BackendRef{
+								BackendObjectReference: gatewayv1beta1.BackendObjectReference{
+									Name: "http-svc-dummy",
+									Port: &port,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// specFromHTTPRoute builds an ingressSpec from route, resolving Endpoints by fetching the
+// Gateway named in route's first ParentRef and reading that Gateway's Status.Addresses - the
+// route's own status carries no address. A Gateway that doesn't exist yet (or hasn't been
+// assigned any addresses) just means no endpoints yet, not an error.
+func (a *gatewayHTTPRouteAPI) specFromHTTPRoute(route *gatewayv1beta1.HTTPRoute) (*ingressSpec, error) {
+	spec := &ingressSpec{
+		Name:             route.Name,
+		Namespace:        route.Namespace,
+		OwnerReferences:  route.OwnerReferences,
+		Annotations:      route.Annotations,
+		IngressClassName: defaultGatewayParentName,
+	}
+	if len(route.Spec.Hostnames) > 0 {
+		spec.Host = string(route.Spec.Hostnames[0])
+	}
+
+	parentName := defaultGatewayParentName
+	if len(route.Spec.ParentRefs) > 0 {
+		parentName = string(route.Spec.ParentRefs[0].Name)
+	}
+
+	gateway, err := a.gateways.Get(context.TODO(), parentName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return spec, nil
+		}
+		return nil, err
+	}
+	for _, address := range gateway.Status.Addresses {
+		spec.Endpoints = append(spec.Endpoints, loadBalancerIngressFromAddress(address.Value))
+	}
+	return spec, nil
+}
+
+func loadBalancerIngressFromAddress(address string) apiv1.LoadBalancerIngress {
+	address = strings.TrimSpace(address)
+	if net.ParseIP(address) != nil {
+		return apiv1.LoadBalancerIngress{IP: address}
+	}
+	return apiv1.LoadBalancerIngress{Hostname: address}
+}
@@ -10,6 +10,7 @@ import (
 	access "github.com/rancher/rancher/pkg/api/customization/globalnamespaceaccess"
 	"github.com/rancher/rancher/pkg/controllers/management/globalnamespacerbac"
 	"github.com/rancher/rancher/pkg/namespace"
+	corev1 "github.com/rancher/types/apis/core/v1"
 	"github.com/rancher/types/apis/management.cattle.io/v3"
 	"github.com/rancher/types/config"
 	"github.com/sirupsen/logrus"
@@ -17,41 +18,155 @@ import (
 	"github.com/rancher/types/client/management/v3"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	clientv1beta1 "k8s.io/client-go/kubernetes/typed/extensions/v1beta1"
+	"k8s.io/client-go/discovery"
+	clientextensionsv1beta1 "k8s.io/client-go/kubernetes/typed/extensions/v1beta1"
+	clientnetworkingv1 "k8s.io/client-go/kubernetes/typed/networking/v1"
+	clientnetworkingv1beta1 "k8s.io/client-go/kubernetes/typed/networking/v1beta1"
 )
 
 const (
 	GlobaldnsController    = "mgmt-global-dns-controller"
 	annotationIngressClass = "kubernetes.io/ingress.class"
+	ingressClassName       = "rancher-external-dns"
+
+	// annotationEndpointClusters records, as a sorted "endpoint=cluster,..." value, which
+	// member cluster contributed each endpoint currently published on the
+	// globaldns-ingress-<name> object.
+	annotationEndpointClusters = "globaldns.cattle.io/endpoint-clusters"
+
+	// ingressVersionNetworkingV1, ingressVersionNetworkingV1beta1 and
+	// ingressVersionExtensionsV1beta1 identify which Ingress API group/version this
+	// Rancher server talks to when managing the globaldns-ingress-<name> object. The value
+	// is detected once at startup via API discovery and cached on GDController.
+	ingressVersionNetworkingV1      = "networking.k8s.io/v1"
+	ingressVersionNetworkingV1beta1 = "networking.k8s.io/v1beta1"
+	ingressVersionExtensionsV1beta1 = "extensions/v1beta1"
+
+	// gatewayAPIGroupVersion is the Gateway API group/version GDController checks for via
+	// discovery to decide whether the HTTPRoute publishing path is available at all.
+	gatewayAPIGroupVersion = "gateway.networking.k8s.io/v1beta1"
 )
 
+// ingressSpec is a version-agnostic description of the globaldns-ingress-<name> object.
+// Each ingressAPI implementation below translates it to and from the concrete Ingress type
+// of the API version it was built against, so the rest of the controller never needs to
+// know which group/version is actually live on the cluster.
+type ingressSpec struct {
+	Name             string
+	Namespace        string
+	OwnerReferences  []metav1.OwnerReference
+	Annotations      map[string]string
+	Host             string
+	IngressClassName string
+	Endpoints        []apiv1.LoadBalancerIngress
+}
+
+// ingressAPI is the single extension point GDController uses to Get, Create and
+// UpdateStatus the globaldns-ingress-<name> object regardless of which Ingress API version
+// is available on the cluster.
+type ingressAPI interface {
+	Get(namespace, name string) (*ingressSpec, error)
+	Create(spec *ingressSpec) (*ingressSpec, error)
+	Update(spec *ingressSpec) (*ingressSpec, error)
+	UpdateStatus(spec *ingressSpec) (*ingressSpec, error)
+}
+
 type GDController struct {
 	globalDNSs        v3.GlobalDNSInterface
 	globalDNSLister   v3.GlobalDNSLister
-	ingresses         clientv1beta1.IngressInterface //need to use client-go IngressInterface to update Ingress.Status field
+	ingressAPI        ingressAPI
+	gatewayAPI        ingressAPI
+	autoGatewayAPI    bool
 	managementContext *config.ManagementContext
 	prtbLister        v3.ProjectRoleTemplateBindingLister
 	rtLister          v3.RoleTemplateLister
+	secretLister      corev1.SecretLister
 }
 
 func newGlobalDNSController(ctx context.Context, mgmt *config.ManagementContext) *GDController {
+	version := detectIngressVersion(mgmt.K8sClient.Discovery())
+	gatewayAvailable := isGatewayAPIAvailable(mgmt.K8sClient.Discovery())
 	n := &GDController{
-		globalDNSs:        mgmt.Management.GlobalDNSs(namespace.GlobalNamespace),
-		globalDNSLister:   mgmt.Management.GlobalDNSs(namespace.GlobalNamespace).Controller().Lister(),
-		ingresses:         mgmt.K8sClient.Extensions().Ingresses(namespace.GlobalNamespace),
+		globalDNSs:      mgmt.Management.GlobalDNSs(namespace.GlobalNamespace),
+		globalDNSLister: mgmt.Management.GlobalDNSs(namespace.GlobalNamespace).Controller().Lister(),
+		ingressAPI:      newIngressAPI(version, mgmt),
+		// autoGatewayAPI covers clusters that serve the Gateway API but have no ingress
+		// controller actually running; GlobalDNS.Spec.UseGatewayAPI selects the Gateway path
+		// explicitly whenever the Gateway API was detected, ingress controller or not.
+		autoGatewayAPI:    gatewayAvailable && !ingressControllerConfigured(mgmt),
 		managementContext: mgmt,
 		prtbLister:        mgmt.Management.ProjectRoleTemplateBindings("").Controller().Lister(),
 		rtLister:          mgmt.Management.RoleTemplates("").Controller().Lister(),
+		secretLister:      mgmt.Core.Secrets(namespace.GlobalNamespace).Controller().Lister(),
 	}
+	if gatewayAvailable {
+		n.gatewayAPI = newGatewayHTTPRouteAPI(mgmt)
+	}
+	logrus.Infof("GlobalDNSController: managing globaldns-ingress objects via %v", version)
 	return n
 }
 
-//sync is called periodically and on real updates
+// detectIngressVersion picks the newest Ingress API group/version this cluster serves,
+// preferring networking/v1 and falling back to networking/v1beta1 and extensions/v1beta1 in
+// turn, so Rancher keeps working against clusters (k8s >= 1.22) where the older groups have
+// been removed as well as against older clusters that don't yet serve networking/v1.
+func detectIngressVersion(disco discovery.DiscoveryInterface) string {
+	// networking.k8s.io/v1 is served (for NetworkPolicy) long before it holds Ingress, so
+	// look for the ingresses resource itself rather than just the group/version.
+	for _, version := range []string{ingressVersionNetworkingV1, ingressVersionNetworkingV1beta1} {
+		resources, err := disco.ServerResourcesForGroupVersion(version)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resources.APIResources {
+			if resource.Name == "ingresses" {
+				return version
+			}
+		}
+	}
+	return ingressVersionExtensionsV1beta1
+}
+
+// isGatewayAPIAvailable reports whether this cluster serves the Gateway API's HTTPRoute
+// resource at all.
+func isGatewayAPIAvailable(disco discovery.DiscoveryInterface) bool {
+	_, err := disco.ServerResourcesForGroupVersion(gatewayAPIGroupVersion)
+	return err == nil
+}
+
+// ingressControllerConfigured reports whether an ingress controller is actually running,
+// rather than merely whether the Ingress API groups are discoverable (virtually every
+// cluster still serves one of those even with zero ingress controller pods). Every ingress
+// controller worth the name registers an IngressClass for itself, so the presence of at
+// least one IngressClass object is used as the live-controller signal instead.
+func ingressControllerConfigured(mgmt *config.ManagementContext) bool {
+	classes, err := mgmt.K8sClient.NetworkingV1().IngressClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return false
+	}
+	return len(classes.Items) > 0
+}
+
+// newIngressAPI returns the ingressAPI implementation matching the detected Ingress version.
+func newIngressAPI(version string, mgmt *config.ManagementContext) ingressAPI {
+	switch version {
+	case ingressVersionNetworkingV1:
+		return &networkingV1IngressAPI{ingresses: mgmt.K8sClient.NetworkingV1().Ingresses(namespace.GlobalNamespace)}
+	case ingressVersionNetworkingV1beta1:
+		return &networkingV1beta1IngressAPI{ingresses: mgmt.K8sClient.NetworkingV1beta1().Ingresses(namespace.GlobalNamespace)}
+	default:
+		return &extensionsV1beta1IngressAPI{ingresses: mgmt.K8sClient.Extensions().Ingresses(namespace.GlobalNamespace)}
+	}
+}
+
+// sync is called periodically and on real updates
 func (n *GDController) sync(key string, obj *v3.GlobalDNS) (runtime.Object, error) {
 	if obj == nil || obj.DeletionTimestamp != nil {
 		return nil, nil
@@ -66,36 +181,34 @@ func (n *GDController) sync(key string, obj *v3.GlobalDNS) (runtime.Object, erro
 		return nil, fmt.Errorf("GlobalDNS %v has no creatorId annotation", metaAccessor.GetName())
 	}
 
-	//check if status.endpoints is set, if yes create a dummy ingress if not already present
-	//if ingress exists, update endpoints if different
-
-	var isUpdate bool
-
-	//check if ingress for this globaldns is already present
-	ingress, err := n.getIngressForGlobalDNS(obj)
-
-	if err != nil && !k8serrors.IsNotFound(err) {
-		return nil, fmt.Errorf("GlobalDNSController: Error listing ingress for the GlobalDNS %v", err)
-	}
-
-	if ingress != nil && err == nil {
-		isUpdate = true
-	}
-
-	if len(obj.Status.Endpoints) == 0 && !isUpdate {
-		return nil, nil
+	//aggregate the weighted, per-cluster endpoint set and drop any that fail an optional
+	//TCP health check, re-queueing so a cluster that comes back healthy later gets retried
+	endpoints, droppedAny := filterReachable(aggregateEndpoints(obj), obj.Spec.HealthCheckPort)
+	if droppedAny {
+		n.globalDNSs.Controller().EnqueueAfter(obj.Namespace, obj.Name, healthCheckRequeueBackoff)
 	}
 
-	if !isUpdate {
-		ingress, err = n.createIngressForGlobalDNS(obj)
-		if err != nil {
-			return nil, fmt.Errorf("GlobalDNSController: Error creating an ingress for the GlobalDNS %v", err)
+	//a freshly-created GlobalDNS with nothing published yet and nothing to publish now has no
+	//publishing or RBAC/member work to do, so skip it the same as before the Publisher
+	//subsystem existed. But once something has actually been published (the ingress-shim or
+	//Gateway object exists), keep reconciling even if endpoints has genuinely dropped to
+	//zero (e.g. the last target cluster/service was removed), so that case still gets
+	//published through and clears the stale record instead of leaving it published forever.
+	if len(endpoints) == 0 {
+		published, err := n.getIngressForGlobalDNS(obj)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("GlobalDNSController: Error checking existing publish state for the GlobalDNS %v", err)
+		}
+		if published == nil {
+			return nil, nil
 		}
 	}
 
-	err = n.updateIngressEndpoints(ingress, obj.Status.Endpoints)
-	if err != nil {
-		return nil, fmt.Errorf("GlobalDNSController: Error updating ingress for the GlobalDNS %v", err)
+	//publish the GlobalDNS endpoints via whichever Publisher matches Spec.ProviderName,
+	//defaulting to the ingress-shim publisher for backward compatibility
+	publisher := n.publisherFor(obj)
+	if err := publisher.Reconcile(context.TODO(), obj.Spec.FQDN, endpoints, defaultDNSTTL); err != nil {
+		return nil, fmt.Errorf("GlobalDNSController: Error publishing endpoints for the GlobalDNS %v", err)
 	}
 
 	groups := globalnamespacerbac.GetMemberGroups(obj.Spec.Members)
@@ -120,8 +233,18 @@ func (n *GDController) sync(key string, obj *v3.GlobalDNS) (runtime.Object, erro
 	return nil, nil
 }
 
-func (n *GDController) getIngressForGlobalDNS(globaldns *v3.GlobalDNS) (*v1beta1.Ingress, error) {
-	ingress, err := n.ingresses.Get(strings.Join([]string{"globaldns-ingress", globaldns.Name}, "-"), metav1.GetOptions{}) //n.Get("", strings.Join([]string{"globaldns-ingress", globaldns.Name}, "-"))
+// ingressAPIFor returns the gateway-backed ingressAPI when globaldns.Spec.UseGatewayAPI is
+// set (or discovery found the Gateway API and no IngressClass exists), falling back to the
+// regular Ingress-backed ingressAPI when no Gateway API implementation is available.
+func (n *GDController) ingressAPIFor(globaldns *v3.GlobalDNS) ingressAPI {
+	if (globaldns.Spec.UseGatewayAPI || n.autoGatewayAPI) && n.gatewayAPI != nil {
+		return n.gatewayAPI
+	}
+	return n.ingressAPI
+}
+
+func (n *GDController) getIngressForGlobalDNS(globaldns *v3.GlobalDNS) (*ingressSpec, error) {
+	ingress, err := n.ingressAPIFor(globaldns).Get(globaldns.Namespace, strings.Join([]string{"globaldns-ingress", globaldns.Name}, "-"))
 	if err != nil {
 		return nil, err
 	}
@@ -132,8 +255,8 @@ func (n *GDController) getIngressForGlobalDNS(globaldns *v3.GlobalDNS) (*v1beta1
 	return nil, nil
 }
 
-func (n *GDController) isIngressOwnedByGlobalDNS(ingress *v1beta1.Ingress, globaldns *v3.GlobalDNS) bool {
-	for i, owners := 0, ingress.GetOwnerReferences(); owners != nil && i < len(owners); i++ {
+func (n *GDController) isIngressOwnedByGlobalDNS(ingress *ingressSpec, globaldns *v3.GlobalDNS) bool {
+	for i, owners := 0, ingress.OwnerReferences; owners != nil && i < len(owners); i++ {
 		if owners[i].UID == globaldns.UID && owners[i].Kind == globaldns.Kind {
 			return true
 		}
@@ -141,9 +264,9 @@ func (n *GDController) isIngressOwnedByGlobalDNS(ingress *v1beta1.Ingress, globa
 	return false
 }
 
-func (n *GDController) createIngressForGlobalDNS(globaldns *v3.GlobalDNS) (*v1beta1.Ingress, error) {
-	ingressSpec := n.generateNewIngressSpec(globaldns)
-	ingressObj, err := n.ingresses.Create(ingressSpec)
+func (n *GDController) createIngressForGlobalDNS(globaldns *v3.GlobalDNS, endpoints []weightedEndpoint) (*ingressSpec, error) {
+	ingressSpec := n.generateNewIngressSpec(globaldns, endpoints)
+	ingressObj, err := n.ingressAPIFor(globaldns).Create(ingressSpec)
 	if err != nil {
 		return nil, err
 	}
@@ -151,58 +274,64 @@ func (n *GDController) createIngressForGlobalDNS(globaldns *v3.GlobalDNS) (*v1be
 	return ingressObj, nil
 }
 
-func (n *GDController) generateNewIngressSpec(globaldns *v3.GlobalDNS) *v1beta1.Ingress {
+func (n *GDController) generateNewIngressSpec(globaldns *v3.GlobalDNS, endpoints []weightedEndpoint) *ingressSpec {
 	controller := true
-	return &v1beta1.Ingress{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: strings.Join([]string{"globaldns-ingress", globaldns.Name}, "-"),
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					Name:       globaldns.Name,
-					APIVersion: "v3",
-					UID:        globaldns.UID,
-					Kind:       globaldns.Kind,
-					Controller: &controller,
-				},
-			},
-			Annotations: map[string]string{
-				annotationIngressClass: "rancher-external-dns",
+	return &ingressSpec{
+		Name:      strings.Join([]string{"globaldns-ingress", globaldns.Name}, "-"),
+		Namespace: globaldns.Namespace,
+		OwnerReferences: []metav1.OwnerReference{
+			{
+				Name:       globaldns.Name,
+				APIVersion: "v3",
+				UID:        globaldns.UID,
+				Kind:       globaldns.Kind,
+				Controller: &controller,
 			},
-			Namespace: globaldns.Namespace,
 		},
-		Spec: v1beta1.IngressSpec{
-			Rules: []v1beta1.IngressRule{
-				{
-					Host: globaldns.Spec.FQDN,
-					IngressRuleValue: v1beta1.IngressRuleValue{
-						HTTP: &v1beta1.HTTPIngressRuleValue{
-							Paths: []v1beta1.HTTPIngressPath{
-								{
-									Backend: v1beta1.IngressBackend{
-										ServiceName: "http-svc-dummy",
-										ServicePort: intstr.IntOrString{
-											Type:   intstr.Int,
-											IntVal: 42,
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-			},
+		Annotations: map[string]string{
+			annotationIngressClass:     ingressClassName,
+			annotationEndpointClusters: endpointClusterAnnotation(endpoints),
 		},
+		Host:             globaldns.Spec.FQDN,
+		IngressClassName: ingressClassName,
+	}
+}
+
+// reconcileIngressSpec re-derives the desired ingress spec for globaldns and, if the FQDN,
+// ingress-class annotation, per-cluster endpoint attribution or owner references have
+// drifted since the ingress was created (most commonly because GlobalDNS.Spec.FQDN was
+// edited or a member cluster's contribution changed), issues an Update so the backing
+// ingress doesn't go stale for the lifetime of the GlobalDNS object.
+func (n *GDController) reconcileIngressSpec(ingress *ingressSpec, globaldns *v3.GlobalDNS, endpoints []weightedEndpoint) (*ingressSpec, error) {
+	desired := n.generateNewIngressSpec(globaldns, endpoints)
+	if ingress.Host == desired.Host &&
+		reflect.DeepEqual(ingress.Annotations, desired.Annotations) &&
+		reflect.DeepEqual(ingress.OwnerReferences, desired.OwnerReferences) {
+		return ingress, nil
+	}
+
+	ingress.Host = desired.Host
+	ingress.Annotations = desired.Annotations
+	ingress.OwnerReferences = desired.OwnerReferences
+	ingress.IngressClassName = desired.IngressClassName
+
+	updated, err := n.ingressAPIFor(globaldns).Update(ingress)
+	if err != nil {
+		return nil, err
 	}
+	logrus.Debugf("GlobalDNSController: Reconciled ingress spec for %v", updated.Name)
+	return updated, nil
 }
 
-func (n *GDController) updateIngressEndpoints(ingress *v1beta1.Ingress, endpoints []string) error {
+func (n *GDController) updateIngressEndpoints(globaldns *v3.GlobalDNS, ingress *ingressSpec, endpoints []string) error {
 
-	if !n.ifEndpointsDiffer(ingress.Status.LoadBalancer.Ingress, endpoints) {
+	if !n.ifEndpointsDiffer(ingress.Endpoints, endpoints) {
+		logrus.Debugf("GlobalDNSController: Endpoints for ingress %v are unchanged, skipping status update", ingress.Name)
 		return nil
 	}
 
-	ingress.Status.LoadBalancer.Ingress = n.sliceToStatus(endpoints)
-	updatedObj, err := n.ingresses.UpdateStatus(ingress)
+	ingress.Endpoints = n.sliceToStatus(endpoints)
+	updatedObj, err := n.ingressAPIFor(globaldns).UpdateStatus(ingress)
 
 	if err != nil {
 		return fmt.Errorf("GlobalDNSController: Error updating Ingress %v", err)
@@ -212,6 +341,8 @@ func (n *GDController) updateIngressEndpoints(ingress *v1beta1.Ingress, endpoint
 	return nil
 }
 
+// ifEndpointsDiffer reports whether endpoints (IPs and/or hostnames) is semantically
+// different from the set already published on the ingress, ignoring ordering.
 func (n *GDController) ifEndpointsDiffer(ingressEps []apiv1.LoadBalancerIngress, endpoints []string) bool {
 	if len(ingressEps) != len(endpoints) {
 		return true
@@ -250,3 +381,299 @@ func (n *GDController) sliceToStatus(endpoints []string) []apiv1.LoadBalancerIng
 	}
 	return lbi
 }
+
+// networkingV1IngressAPI implements ingressAPI against the networking.k8s.io/v1 Ingress
+// API, the default on k8s >= 1.19 and the only Ingress API left on k8s >= 1.22.
+type networkingV1IngressAPI struct {
+	ingresses clientnetworkingv1.IngressInterface
+}
+
+func (a *networkingV1IngressAPI) Get(namespace, name string) (*ingressSpec, error) {
+	ingress, err := a.ingresses.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return networkingV1SpecFromIngress(ingress), nil
+}
+
+func (a *networkingV1IngressAPI) Create(spec *ingressSpec) (*ingressSpec, error) {
+	pathType := networkingv1.PathTypeImplementationSpecific
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            spec.Name,
+			Namespace:       spec.Namespace,
+			OwnerReferences: spec.OwnerReferences,
+			Annotations:     spec.Annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: &spec.IngressClassName,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: spec.Host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "http-svc-dummy",
+											Port: networkingv1.ServiceBackendPort{Number: 42},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	created, err := a.ingresses.Create(context.TODO(), ingress, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return networkingV1SpecFromIngress(created), nil
+}
+
+func (a *networkingV1IngressAPI) Update(spec *ingressSpec) (*ingressSpec, error) {
+	ingress, err := a.ingresses.Get(context.TODO(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ingress.Annotations = spec.Annotations
+	ingress.OwnerReferences = spec.OwnerReferences
+	ingress.Spec.IngressClassName = &spec.IngressClassName
+	if len(ingress.Spec.Rules) > 0 {
+		ingress.Spec.Rules[0].Host = spec.Host
+	}
+	updated, err := a.ingresses.Update(context.TODO(), ingress, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return networkingV1SpecFromIngress(updated), nil
+}
+
+func (a *networkingV1IngressAPI) UpdateStatus(spec *ingressSpec) (*ingressSpec, error) {
+	ingress, err := a.ingresses.Get(context.TODO(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ingress.Status.LoadBalancer.Ingress = spec.Endpoints
+	updated, err := a.ingresses.UpdateStatus(context.TODO(), ingress, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return networkingV1SpecFromIngress(updated), nil
+}
+
+func networkingV1SpecFromIngress(ingress *networkingv1.Ingress) *ingressSpec {
+	spec := &ingressSpec{
+		Name:            ingress.Name,
+		Namespace:       ingress.Namespace,
+		OwnerReferences: ingress.OwnerReferences,
+		Annotations:     ingress.Annotations,
+		Endpoints:       ingress.Status.LoadBalancer.Ingress,
+	}
+	if len(ingress.Spec.Rules) > 0 {
+		spec.Host = ingress.Spec.Rules[0].Host
+	}
+	if ingress.Spec.IngressClassName != nil {
+		spec.IngressClassName = *ingress.Spec.IngressClassName
+	}
+	return spec
+}
+
+// networkingV1beta1IngressAPI implements ingressAPI against the
+// networking.k8s.io/v1beta1 Ingress API, served by k8s 1.14-1.21.
+type networkingV1beta1IngressAPI struct {
+	ingresses clientnetworkingv1beta1.IngressInterface
+}
+
+func (a *networkingV1beta1IngressAPI) Get(namespace, name string) (*ingressSpec, error) {
+	ingress, err := a.ingresses.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return networkingV1beta1SpecFromIngress(ingress), nil
+}
+
+func (a *networkingV1beta1IngressAPI) Create(spec *ingressSpec) (*ingressSpec, error) {
+	pathType := networkingv1beta1.PathTypeImplementationSpecific
+	ingress := &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            spec.Name,
+			Namespace:       spec.Namespace,
+			OwnerReferences: spec.OwnerReferences,
+			Annotations:     spec.Annotations,
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			IngressClassName: &spec.IngressClassName,
+			Rules: []networkingv1beta1.IngressRule{
+				{
+					Host: spec.Host,
+					IngressRuleValue: networkingv1beta1.IngressRuleValue{
+						HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+							Paths: []networkingv1beta1.HTTPIngressPath{
+								{
+									PathType: &pathType,
+									Backend: networkingv1beta1.IngressBackend{
+										ServiceName: "http-svc-dummy",
+										ServicePort: intstr.IntOrString{Type: intstr.Int, IntVal: 42},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	created, err := a.ingresses.Create(context.TODO(), ingress, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return networkingV1beta1SpecFromIngress(created), nil
+}
+
+func (a *networkingV1beta1IngressAPI) Update(spec *ingressSpec) (*ingressSpec, error) {
+	ingress, err := a.ingresses.Get(context.TODO(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ingress.Annotations = spec.Annotations
+	ingress.OwnerReferences = spec.OwnerReferences
+	ingress.Spec.IngressClassName = &spec.IngressClassName
+	if len(ingress.Spec.Rules) > 0 {
+		ingress.Spec.Rules[0].Host = spec.Host
+	}
+	updated, err := a.ingresses.Update(context.TODO(), ingress, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return networkingV1beta1SpecFromIngress(updated), nil
+}
+
+func (a *networkingV1beta1IngressAPI) UpdateStatus(spec *ingressSpec) (*ingressSpec, error) {
+	ingress, err := a.ingresses.Get(context.TODO(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ingress.Status.LoadBalancer.Ingress = spec.Endpoints
+	updated, err := a.ingresses.UpdateStatus(context.TODO(), ingress, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return networkingV1beta1SpecFromIngress(updated), nil
+}
+
+func networkingV1beta1SpecFromIngress(ingress *networkingv1beta1.Ingress) *ingressSpec {
+	spec := &ingressSpec{
+		Name:            ingress.Name,
+		Namespace:       ingress.Namespace,
+		OwnerReferences: ingress.OwnerReferences,
+		Annotations:     ingress.Annotations,
+		Endpoints:       ingress.Status.LoadBalancer.Ingress,
+	}
+	if len(ingress.Spec.Rules) > 0 {
+		spec.Host = ingress.Spec.Rules[0].Host
+	}
+	if ingress.Spec.IngressClassName != nil {
+		spec.IngressClassName = *ingress.Spec.IngressClassName
+	}
+	return spec
+}
+
+// extensionsV1beta1IngressAPI implements ingressAPI against the legacy
+// extensions/v1beta1 Ingress API, kept only for clusters older than k8s 1.14 that don't
+// yet serve networking.k8s.io at all.
+type extensionsV1beta1IngressAPI struct {
+	ingresses clientextensionsv1beta1.IngressInterface
+}
+
+func (a *extensionsV1beta1IngressAPI) Get(namespace, name string) (*ingressSpec, error) {
+	ingress, err := a.ingresses.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return extensionsV1beta1SpecFromIngress(ingress), nil
+}
+
+func (a *extensionsV1beta1IngressAPI) Create(spec *ingressSpec) (*ingressSpec, error) {
+	ingress := &v1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            spec.Name,
+			Namespace:       spec.Namespace,
+			OwnerReferences: spec.OwnerReferences,
+			Annotations:     spec.Annotations,
+		},
+		Spec: v1beta1.IngressSpec{
+			Rules: []v1beta1.IngressRule{
+				{
+					Host: spec.Host,
+					IngressRuleValue: v1beta1.IngressRuleValue{
+						HTTP: &v1beta1.HTTPIngressRuleValue{
+							Paths: []v1beta1.HTTPIngressPath{
+								{
+									Backend: v1beta1.IngressBackend{
+										ServiceName: "http-svc-dummy",
+										ServicePort: intstr.IntOrString{Type: intstr.Int, IntVal: 42},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	created, err := a.ingresses.Create(context.TODO(), ingress, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return extensionsV1beta1SpecFromIngress(created), nil
+}
+
+func (a *extensionsV1beta1IngressAPI) Update(spec *ingressSpec) (*ingressSpec, error) {
+	ingress, err := a.ingresses.Get(context.TODO(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ingress.Annotations = spec.Annotations
+	ingress.OwnerReferences = spec.OwnerReferences
+	if len(ingress.Spec.Rules) > 0 {
+		ingress.Spec.Rules[0].Host = spec.Host
+	}
+	updated, err := a.ingresses.Update(context.TODO(), ingress, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return extensionsV1beta1SpecFromIngress(updated), nil
+}
+
+func (a *extensionsV1beta1IngressAPI) UpdateStatus(spec *ingressSpec) (*ingressSpec, error) {
+	ingress, err := a.ingresses.Get(context.TODO(), spec.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ingress.Status.LoadBalancer.Ingress = spec.Endpoints
+	updated, err := a.ingresses.UpdateStatus(context.TODO(), ingress, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return extensionsV1beta1SpecFromIngress(updated), nil
+}
+
+func extensionsV1beta1SpecFromIngress(ingress *v1beta1.Ingress) *ingressSpec {
+	spec := &ingressSpec{
+		Name:            ingress.Name,
+		Namespace:       ingress.Namespace,
+		OwnerReferences: ingress.OwnerReferences,
+		Annotations:     ingress.Annotations,
+		Endpoints:       ingress.Status.LoadBalancer.Ingress,
+	}
+	if len(ingress.Spec.Rules) > 0 {
+		spec.Host = ingress.Spec.Rules[0].Host
+	}
+	return spec
+}
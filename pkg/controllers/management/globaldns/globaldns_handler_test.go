@@ -0,0 +1,133 @@
+package globaldns
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rancher/types/apis/management.cattle.io/v3"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+func TestIfEndpointsDiffer(t *testing.T) {
+	n := &GDController{}
+	tests := []struct {
+		name       string
+		ingressEps []apiv1.LoadBalancerIngress
+		endpoints  []string
+		want       bool
+	}{
+		{
+			name:       "identical IP sets in different order don't differ",
+			ingressEps: []apiv1.LoadBalancerIngress{{IP: "1.1.1.1"}, {IP: "2.2.2.2"}},
+			endpoints:  []string{"2.2.2.2", "1.1.1.1"},
+			want:       false,
+		},
+		{
+			name:       "a hostname endpoint is matched the same as an IP one",
+			ingressEps: []apiv1.LoadBalancerIngress{{Hostname: "lb.example.com"}},
+			endpoints:  []string{"lb.example.com"},
+			want:       false,
+		},
+		{
+			name:       "a different endpoint set differs",
+			ingressEps: []apiv1.LoadBalancerIngress{{IP: "1.1.1.1"}},
+			endpoints:  []string{"2.2.2.2"},
+			want:       true,
+		},
+		{
+			name:       "a different count differs",
+			ingressEps: []apiv1.LoadBalancerIngress{{IP: "1.1.1.1"}, {IP: "2.2.2.2"}},
+			endpoints:  []string{"1.1.1.1"},
+			want:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := n.ifEndpointsDiffer(tt.ingressEps, tt.endpoints); got != tt.want {
+				t.Errorf("ifEndpointsDiffer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSliceToStatus(t *testing.T) {
+	n := &GDController{}
+	got := n.sliceToStatus([]string{"1.1.1.1", "lb.example.com"})
+	want := []apiv1.LoadBalancerIngress{{IP: "1.1.1.1"}, {Hostname: "lb.example.com"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sliceToStatus() = %+v, want %+v", got, want)
+	}
+}
+
+// fakeIngressAPI is a minimal in-memory ingressAPI used to exercise reconcileIngressSpec's
+// drift detection without a real Ingress/HTTPRoute backend.
+type fakeIngressAPI struct {
+	spec         *ingressSpec
+	updateCalled bool
+}
+
+func (f *fakeIngressAPI) Get(namespace, name string) (*ingressSpec, error) { return f.spec, nil }
+func (f *fakeIngressAPI) Create(spec *ingressSpec) (*ingressSpec, error) {
+	f.spec = spec
+	return spec, nil
+}
+func (f *fakeIngressAPI) Update(spec *ingressSpec) (*ingressSpec, error) {
+	f.updateCalled = true
+	f.spec = spec
+	return spec, nil
+}
+func (f *fakeIngressAPI) UpdateStatus(spec *ingressSpec) (*ingressSpec, error) {
+	f.spec = spec
+	return spec, nil
+}
+
+func TestReconcileIngressSpec(t *testing.T) {
+	globaldns := &v3.GlobalDNS{Spec: v3.GlobalDNSSpec{FQDN: "a.example.com"}}
+	endpoints := []weightedEndpoint{{Endpoint: "1.1.1.1", Cluster: "clusterA", Weight: 100}}
+
+	t.Run("no-ops when nothing has drifted", func(t *testing.T) {
+		fake := &fakeIngressAPI{}
+		n := &GDController{ingressAPI: fake}
+		current := n.generateNewIngressSpec(globaldns, endpoints)
+
+		if _, err := n.reconcileIngressSpec(current, globaldns, endpoints); err != nil {
+			t.Fatalf("reconcileIngressSpec() error = %v", err)
+		}
+		if fake.updateCalled {
+			t.Errorf("reconcileIngressSpec() called Update(), want no-op when spec is unchanged")
+		}
+	})
+
+	t.Run("updates when FQDN has drifted", func(t *testing.T) {
+		fake := &fakeIngressAPI{}
+		n := &GDController{ingressAPI: fake}
+		stale := n.generateNewIngressSpec(globaldns, endpoints)
+		stale.Host = "old.example.com"
+
+		updated, err := n.reconcileIngressSpec(stale, globaldns, endpoints)
+		if err != nil {
+			t.Fatalf("reconcileIngressSpec() error = %v", err)
+		}
+		if !fake.updateCalled {
+			t.Fatalf("reconcileIngressSpec() didn't call Update(), want it to push the drifted host")
+		}
+		if updated.Host != globaldns.Spec.FQDN {
+			t.Errorf("reconcileIngressSpec() Host = %v, want %v", updated.Host, globaldns.Spec.FQDN)
+		}
+	})
+
+	t.Run("updates when per-cluster endpoint attribution has drifted", func(t *testing.T) {
+		fake := &fakeIngressAPI{}
+		n := &GDController{ingressAPI: fake}
+		current := n.generateNewIngressSpec(globaldns, endpoints)
+
+		changed := []weightedEndpoint{{Endpoint: "1.1.1.1", Cluster: "clusterB", Weight: 100}}
+		if _, err := n.reconcileIngressSpec(current, globaldns, changed); err != nil {
+			t.Fatalf("reconcileIngressSpec() error = %v", err)
+		}
+		if !fake.updateCalled {
+			t.Errorf("reconcileIngressSpec() didn't call Update(), want it to push the drifted endpointClusters annotation")
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package globaldns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rancher/types/apis/management.cattle.io/v3"
+	"golang.org/x/oauth2/google"
+	dns "google.golang.org/api/dns/v1"
+	"google.golang.org/api/option"
+)
+
+// googleCloudDNSPublisher publishes GlobalDNS endpoints directly to a Google CloudDNS
+// managed zone, bypassing the dummy-ingress/external-dns shim.
+type googleCloudDNSPublisher struct {
+	controller *GDController
+	globaldns  *v3.GlobalDNS
+}
+
+func newGoogleCloudDNSPublisher(controller *GDController, globaldns *v3.GlobalDNS) Publisher {
+	return &googleCloudDNSPublisher{controller: controller, globaldns: globaldns}
+}
+
+func (p *googleCloudDNSPublisher) Reconcile(ctx context.Context, fqdn string, endpoints []weightedEndpoint, ttl int) error {
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	creds, err := p.controller.dnsProviderCredentials(p.globaldns, "project", "managedZone", "serviceAccountJSON")
+	if err != nil {
+		return fmt.Errorf("googleclouddns: %v", err)
+	}
+	project, managedZone, serviceAccountJSON := creds[0], creds[1], creds[2]
+
+	jwtConfig, err := google.JWTConfigFromJSON([]byte(serviceAccountJSON), dns.NdevClouddnsReadwriteScope)
+	if err != nil {
+		return fmt.Errorf("googleclouddns: error parsing service account credentials: %v", err)
+	}
+
+	svc, err := dns.NewService(ctx, option.WithHTTPClient(jwtConfig.Client(ctx)))
+	if err != nil {
+		return fmt.Errorf("googleclouddns: error creating client: %v", err)
+	}
+
+	name := fqdn + "."
+	change := &dns.Change{}
+
+	// CloudDNS record sets can't mix A and CNAME values, so build (and replace) one record
+	// set per record type actually present among endpoints (hostname endpoints, e.g.
+	// classic ELB/ALB, need CNAME rather than A).
+	for recordType, byType := range groupByRecordType(endpoints) {
+		rrset := &dns.ResourceRecordSet{Name: name, Type: recordType, Ttl: int64(ttl)}
+
+		// CloudDNS expresses weighted routing as a WRR policy of one item per weight group
+		// rather than a flat Rrdatas list, so give each contributing cluster its own item.
+		groups := groupBySetIdentifier(byType)
+		if len(groups) > 1 {
+			items := make([]*dns.RRSetRoutingPolicyWrrPolicyItem, 0, len(groups))
+			for _, group := range groups {
+				items = append(items, &dns.RRSetRoutingPolicyWrrPolicyItem{
+					Weight: float64(group[0].Weight),
+					Rrdata: endpointValues(group),
+				})
+			}
+			rrset.RoutingPolicy = &dns.RRSetRoutingPolicy{Wrr: &dns.RRSetRoutingPolicyWrrPolicy{Items: items}}
+		} else {
+			rrset.Rrdatas = endpointValues(byType)
+		}
+		change.Additions = append(change.Additions, rrset)
+
+		existing, err := svc.ResourceRecordSets.List(project, managedZone).Name(name).Type(recordType).Do()
+		if err != nil {
+			return fmt.Errorf("googleclouddns: error listing %v record sets for %v: %v", recordType, fqdn, err)
+		}
+		change.Deletions = append(change.Deletions, existing.Rrsets...)
+	}
+
+	if _, err := svc.Changes.Create(project, managedZone, change).Do(); err != nil {
+		return fmt.Errorf("googleclouddns: error upserting record sets for %v: %v", fqdn, err)
+	}
+	return nil
+}
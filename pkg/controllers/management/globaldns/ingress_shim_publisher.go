@@ -0,0 +1,57 @@
+package globaldns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rancher/types/apis/management.cattle.io/v3"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ingressShimPublisher is the original, default Publisher: it creates/updates the
+// globaldns-ingress-<name> object annotated kubernetes.io/ingress.class=rancher-external-dns
+// so that an external-dns deployment watching that annotation publishes the endpoints to
+// whichever provider external-dns itself is configured against. On clusters where
+// GlobalDNS.Spec.UseGatewayAPI is set (or discovery found the Gateway API and no
+// IngressClass exists) GDController.ingressAPIFor transparently swaps in the HTTPRoute-backed
+// ingressAPI instead, so this publisher's logic is unchanged either way.
+type ingressShimPublisher struct {
+	controller *GDController
+	globaldns  *v3.GlobalDNS
+}
+
+func newIngressShimPublisher(controller *GDController, globaldns *v3.GlobalDNS) Publisher {
+	return &ingressShimPublisher{controller: controller, globaldns: globaldns}
+}
+
+func (p *ingressShimPublisher) Reconcile(ctx context.Context, fqdn string, endpoints []weightedEndpoint, ttl int) error {
+	if len(endpoints) == 0 && len(aggregateEndpoints(p.globaldns)) > 0 {
+		// Nothing reachable to publish right now (every endpoint just failed its health
+		// check). GDController.sync already re-queues to retry filterReachable, so
+		// leave any already-published ingress/status alone instead of wiping it to empty -
+		// the same no-op-on-empty behavior the direct-API publishers (route53/cloudflare/
+		// googleclouddns/coredns) use to avoid deleting every record on one bad probe.
+		// An endpoint set that is empty before health checking (the last target was
+		// removed) falls through and clears the published status.
+		return nil
+	}
+
+	ingress, err := p.controller.getIngressForGlobalDNS(p.globaldns)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("error listing ingress for the GlobalDNS %v", err)
+	}
+
+	if ingress == nil {
+		ingress, err = p.controller.createIngressForGlobalDNS(p.globaldns, endpoints)
+		if err != nil {
+			return fmt.Errorf("error creating an ingress for the GlobalDNS %v", err)
+		}
+	} else {
+		ingress, err = p.controller.reconcileIngressSpec(ingress, p.globaldns, endpoints)
+		if err != nil {
+			return fmt.Errorf("error reconciling ingress spec for the GlobalDNS %v", err)
+		}
+	}
+
+	return p.controller.updateIngressEndpoints(p.globaldns, ingress, endpointValues(endpoints))
+}
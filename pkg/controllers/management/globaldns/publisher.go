@@ -0,0 +1,90 @@
+package globaldns
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/types/apis/management.cattle.io/v3"
+)
+
+// defaultDNSTTL is the TTL GDController.sync passes to the Publisher for every GlobalDNS; no
+// per-object TTL is read.
+const defaultDNSTTL = 300
+
+const (
+	providerNameRoute53        = "route53"
+	providerNameCloudflare     = "cloudflare"
+	providerNameGoogleCloudDNS = "googleclouddns"
+	providerNameCoreDNS        = "coredns"
+)
+
+// Publisher reconciles the FQDN and weighted endpoint set of a GlobalDNS against a concrete
+// DNS backend. GDController.sync picks an implementation based on GlobalDNS.Spec.ProviderName
+// and hands off the actual publishing work to it, so new backends can be added without
+// changing sync or the ingress-shim path other GlobalDNS objects keep relying on.
+type Publisher interface {
+	// Reconcile publishes fqdn -> endpoints (with the given ttl, where the backend supports
+	// one) and must be safe to call repeatedly with an unchanged desired state. Backends
+	// that understand weighted record sets (Route53, CloudDNS) may use weightedEndpoint's
+	// Weight; others should fall back to endpointValues(endpoints).
+	Reconcile(ctx context.Context, fqdn string, endpoints []weightedEndpoint, ttl int) error
+}
+
+// publisherFor returns the Publisher for globaldns.Spec.ProviderName. An empty or
+// unrecognized ProviderName keeps using the ingress-shim publisher, preserving the original
+// behavior for GlobalDNS objects that rely on external-dns watching the
+// globaldns-ingress-<name> object.
+func (n *GDController) publisherFor(globaldns *v3.GlobalDNS) Publisher {
+	switch globaldns.Spec.ProviderName {
+	case providerNameRoute53:
+		return newRoute53Publisher(n, globaldns)
+	case providerNameCloudflare:
+		return newCloudflarePublisher(n, globaldns)
+	case providerNameGoogleCloudDNS:
+		return newGoogleCloudDNSPublisher(n, globaldns)
+	case providerNameCoreDNS:
+		return newCoreDNSPublisher(n, globaldns)
+	default:
+		return newIngressShimPublisher(n, globaldns)
+	}
+}
+
+// dnsProviderCredentials reads the named keys out of the Secret referenced by
+// globaldns.Spec.ProviderCredentialSecret, in the global namespace. GlobalDNSProvider-backed
+// publishers (Route53, CloudFlare, Google CloudDNS, CoreDNS) store their API credentials this
+// way, the same convention used for other cattle-global-data credentials. ProviderName only
+// selects which Publisher implementation to dispatch to; ProviderCredentialSecret is what
+// actually names the Secret, so two GlobalDNS objects using the same provider type can point
+// at different credential sets (e.g. two AWS accounts both publishing via Route53).
+func (n *GDController) dnsProviderCredentials(globaldns *v3.GlobalDNS, keys ...string) ([]string, error) {
+	secretName := globaldns.Spec.ProviderCredentialSecret
+	if secretName == "" {
+		return nil, fmt.Errorf("globaldns %v has no ProviderCredentialSecret set for provider %v", globaldns.Name, globaldns.Spec.ProviderName)
+	}
+
+	secret, err := n.secretLister.Get(namespace.GlobalNamespace, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("error looking up credentials secret %v: %v", secretName, err)
+	}
+
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value, ok := secret.Data[key]
+		if !ok {
+			return nil, fmt.Errorf("credentials secret %v is missing key %v", secretName, key)
+		}
+		values = append(values, string(value))
+	}
+	return values, nil
+}
+
+// isHostnameEndpoint reports whether endpoint is a hostname (classic AWS ELB/ALB, etc.)
+// rather than an IP literal, the same test GDController.sliceToStatus uses to pick between
+// LoadBalancerIngress.Hostname and .IP for the ingress-shim path. Direct-API publishers use
+// it to pick between an A record and a CNAME record; IPv6 literals get an A record too, as no
+// publisher here emits AAAA records.
+func isHostnameEndpoint(endpoint string) bool {
+	return net.ParseIP(endpoint) == nil
+}
@@ -0,0 +1,111 @@
+package globaldns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/rancher/types/apis/management.cattle.io/v3"
+)
+
+// route53Publisher publishes GlobalDNS endpoints directly to an AWS Route53 hosted zone,
+// bypassing the dummy-ingress/external-dns shim for clusters that don't want to run
+// external-dns at all.
+type route53Publisher struct {
+	controller *GDController
+	globaldns  *v3.GlobalDNS
+}
+
+func newRoute53Publisher(controller *GDController, globaldns *v3.GlobalDNS) Publisher {
+	return &route53Publisher{controller: controller, globaldns: globaldns}
+}
+
+func (p *route53Publisher) Reconcile(ctx context.Context, fqdn string, endpoints []weightedEndpoint, ttl int) error {
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	creds, err := p.controller.dnsProviderCredentials(p.globaldns, "accessKey", "secretKey", "hostedZoneID")
+	if err != nil {
+		return fmt.Errorf("route53: %v", err)
+	}
+	accessKey, secretKey, hostedZoneID := creds[0], creds[1], creds[2]
+
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials(accessKey, secretKey, ""),
+	})
+	if err != nil {
+		return fmt.Errorf("route53: error creating AWS session: %v", err)
+	}
+
+	// Route53 weighted routing needs one record set per weight group, distinguished by
+	// SetIdentifier, so give each contributing cluster its own weighted record set instead
+	// of flattening everything into a single unweighted one. A record set also can't mix A
+	// and CNAME values, so within each group split further by record type (hostname
+	// endpoints, e.g. classic ELB/ALB, need CNAME rather than A) and qualify SetIdentifier
+	// with the type to keep it unique if a single cluster ever reports both.
+	changes := []*route53.Change{}
+	for identifier, group := range groupBySetIdentifier(endpoints) {
+		for recordType, byType := range groupByRecordType(group) {
+			resourceRecords := make([]*route53.ResourceRecord, 0, len(byType))
+			for _, ep := range byType {
+				resourceRecords = append(resourceRecords, &route53.ResourceRecord{Value: aws.String(ep.Endpoint)})
+			}
+			setIdentifier := identifier
+			if recordType != route53.RRTypeA {
+				setIdentifier += "-" + recordType
+			}
+			changes = append(changes, &route53.Change{
+				Action: aws.String(route53.ChangeActionUpsert),
+				ResourceRecordSet: &route53.ResourceRecordSet{
+					Name:            aws.String(fqdn),
+					Type:            aws.String(recordType),
+					TTL:             aws.Int64(int64(ttl)),
+					SetIdentifier:   aws.String(setIdentifier),
+					Weight:          aws.Int64(byType[0].Weight),
+					ResourceRecords: resourceRecords,
+				},
+			})
+		}
+	}
+
+	_, err = route53.New(sess).ChangeResourceRecordSetsWithContext(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(hostedZoneID),
+		ChangeBatch:  &route53.ChangeBatch{Changes: changes},
+	})
+	if err != nil {
+		return fmt.Errorf("route53: error upserting record sets for %v: %v", fqdn, err)
+	}
+	return nil
+}
+
+// groupBySetIdentifier buckets endpoints by cluster (falling back to "default" for
+// endpoints with no known cluster), one Route53 SetIdentifier per bucket.
+func groupBySetIdentifier(endpoints []weightedEndpoint) map[string][]weightedEndpoint {
+	groups := map[string][]weightedEndpoint{}
+	for _, ep := range endpoints {
+		identifier := ep.Cluster
+		if identifier == "" {
+			identifier = "default"
+		}
+		groups[identifier] = append(groups[identifier], ep)
+	}
+	return groups
+}
+
+// groupByRecordType splits endpoints into Route53 RRTypeA and RRTypeCname buckets, since a
+// single record set can't mix IP and hostname values.
+func groupByRecordType(endpoints []weightedEndpoint) map[string][]weightedEndpoint {
+	groups := map[string][]weightedEndpoint{}
+	for _, ep := range endpoints {
+		recordType := route53.RRTypeA
+		if isHostnameEndpoint(ep.Endpoint) {
+			recordType = route53.RRTypeCname
+		}
+		groups[recordType] = append(groups[recordType], ep)
+	}
+	return groups
+}
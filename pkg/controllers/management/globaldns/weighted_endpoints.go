@@ -0,0 +1,134 @@
+package globaldns
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/types/apis/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultEndpointWeight     = int64(100)
+	healthCheckDialTimeout    = 2 * time.Second
+	healthCheckRequeueBackoff = 30 * time.Second
+)
+
+// weightedEndpoint is one member cluster's contribution to a GlobalDNS record set: the
+// endpoint itself, the cluster it came from (when known) and the weight downstream
+// weight-aware DNS providers (Route53, CloudDNS) should give it.
+type weightedEndpoint struct {
+	Endpoint string
+	Cluster  string
+	Weight   int64
+}
+
+// aggregateEndpoints builds the weighted, per-cluster endpoint list for globaldns. When
+// Status.ClusterEndpoints hasn't been populated yet (clusters too old to report their
+// contribution separately) it falls back to treating every entry in Status.Endpoints as
+// coming from an unknown cluster at the default weight, so plain round-robin keeps working
+// exactly as before.
+func aggregateEndpoints(globaldns *v3.GlobalDNS) []weightedEndpoint {
+	clusterEndpoints := globaldns.Status.ClusterEndpoints
+	if len(clusterEndpoints) == 0 {
+		endpoints := make([]weightedEndpoint, 0, len(globaldns.Status.Endpoints))
+		for _, ep := range globaldns.Status.Endpoints {
+			endpoints = append(endpoints, weightedEndpoint{Endpoint: ep, Weight: defaultEndpointWeight})
+		}
+		return endpoints
+	}
+
+	weighted := make([]weightedEndpoint, 0, len(globaldns.Status.Endpoints))
+	for cluster, endpoints := range clusterEndpoints {
+		weight := weightForCluster(globaldns, cluster)
+		for _, ep := range endpoints {
+			weighted = append(weighted, weightedEndpoint{Endpoint: ep, Cluster: cluster, Weight: weight})
+		}
+	}
+	return weighted
+}
+
+// weightForCluster resolves the weight hint for cluster from GlobalDNS.Spec.Weights, which
+// may key by cluster name directly or, failing that, by one of the projects GlobalDNS is
+// scoped to within that cluster. Clusters with no matching hint get the default weight, so
+// existing GlobalDNS objects that never set Spec.Weights keep splitting traffic evenly.
+func weightForCluster(globaldns *v3.GlobalDNS, cluster string) int64 {
+	if w, ok := globaldns.Spec.Weights[cluster]; ok {
+		return w
+	}
+	for _, project := range globaldns.Spec.ProjectNames {
+		if strings.HasPrefix(project, cluster+":") {
+			if w, ok := globaldns.Spec.Weights[project]; ok {
+				return w
+			}
+		}
+	}
+	return defaultEndpointWeight
+}
+
+// filterReachable TCP-probes each endpoint on port and drops the ones that don't accept a
+// connection within healthCheckDialTimeout, so GlobalDNS never advertises a member
+// cluster's endpoint while it's unreachable. A port of 0 disables health checking and
+// returns endpoints unchanged. Dials run concurrently, one goroutine per endpoint, so a
+// single slow or unreachable cluster blocks sync() for at most healthCheckDialTimeout
+// instead of serializing behind every other endpoint's dial.
+func filterReachable(endpoints []weightedEndpoint, port int64) (reachable []weightedEndpoint, droppedAny bool) {
+	if port <= 0 {
+		return endpoints, false
+	}
+
+	ok := make([]bool, len(endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range endpoints {
+		wg.Add(1)
+		go func(i int, ep weightedEndpoint) {
+			defer wg.Done()
+			address := net.JoinHostPort(ep.Endpoint, strconv.FormatInt(port, 10))
+			conn, err := net.DialTimeout("tcp", address, healthCheckDialTimeout)
+			if err != nil {
+				logrus.Debugf("GlobalDNSController: endpoint %v (cluster %v) failed TCP health check on port %v: %v", ep.Endpoint, ep.Cluster, port, err)
+				return
+			}
+			conn.Close()
+			ok[i] = true
+		}(i, ep)
+	}
+	wg.Wait()
+
+	for i, ep := range endpoints {
+		if ok[i] {
+			reachable = append(reachable, ep)
+		} else {
+			droppedAny = true
+		}
+	}
+	return reachable, droppedAny
+}
+
+// endpointClusterAnnotation renders endpoints as the "endpoint=cluster,..." value stored in
+// annotationEndpointClusters, sorted for a stable diff between syncs.
+func endpointClusterAnnotation(endpoints []weightedEndpoint) string {
+	pairs := make([]string, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if ep.Cluster == "" {
+			continue
+		}
+		pairs = append(pairs, ep.Endpoint+"="+ep.Cluster)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// endpointValues returns the plain endpoint strings, discarding cluster/weight metadata, for
+// backends that have no notion of weighted records.
+func endpointValues(endpoints []weightedEndpoint) []string {
+	values := make([]string, 0, len(endpoints))
+	for _, ep := range endpoints {
+		values = append(values, ep.Endpoint)
+	}
+	return values
+}
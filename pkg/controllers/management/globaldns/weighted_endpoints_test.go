@@ -0,0 +1,132 @@
+package globaldns
+
+import (
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/rancher/types/apis/management.cattle.io/v3"
+)
+
+func TestAggregateEndpoints(t *testing.T) {
+	tests := []struct {
+		name      string
+		globaldns *v3.GlobalDNS
+		want      []weightedEndpoint
+	}{
+		{
+			name: "falls back to Status.Endpoints at the default weight when ClusterEndpoints is unset",
+			globaldns: &v3.GlobalDNS{
+				Status: v3.GlobalDNSStatus{Endpoints: []string{"1.1.1.1", "2.2.2.2"}},
+			},
+			want: []weightedEndpoint{
+				{Endpoint: "1.1.1.1", Weight: defaultEndpointWeight},
+				{Endpoint: "2.2.2.2", Weight: defaultEndpointWeight},
+			},
+		},
+		{
+			name: "attributes endpoints to their cluster and weight when ClusterEndpoints is set",
+			globaldns: &v3.GlobalDNS{
+				Spec:   v3.GlobalDNSSpec{Weights: map[string]int64{"clusterA": 50}},
+				Status: v3.GlobalDNSStatus{ClusterEndpoints: map[string][]string{"clusterA": {"1.1.1.1"}}},
+			},
+			want: []weightedEndpoint{
+				{Endpoint: "1.1.1.1", Cluster: "clusterA", Weight: 50},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := aggregateEndpoints(tt.globaldns)
+			if !sameEndpoints(got, tt.want) {
+				t.Errorf("aggregateEndpoints() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeightForCluster(t *testing.T) {
+	globaldns := &v3.GlobalDNS{
+		Spec: v3.GlobalDNSSpec{
+			Weights:      map[string]int64{"clusterA": 10, "clusterB:projectX": 20},
+			ProjectNames: []string{"clusterB:projectX"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		cluster string
+		want    int64
+	}{
+		{name: "direct cluster match", cluster: "clusterA", want: 10},
+		{name: "falls back to a scoped project match", cluster: "clusterB", want: 20},
+		{name: "defaults when nothing matches", cluster: "clusterC", want: defaultEndpointWeight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weightForCluster(globaldns, tt.cluster); got != tt.want {
+				t.Errorf("weightForCluster(%v) = %v, want %v", tt.cluster, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterReachable(t *testing.T) {
+	endpoints := []weightedEndpoint{
+		{Endpoint: "127.0.0.1", Cluster: "a", Weight: 1},
+		{Endpoint: "127.0.0.1", Cluster: "b", Weight: 1},
+	}
+
+	t.Run("a port of 0 disables health checking", func(t *testing.T) {
+		reachable, droppedAny := filterReachable(endpoints, 0)
+		if droppedAny || !sameEndpoints(reachable, endpoints) {
+			t.Errorf("filterReachable() = %+v, %v, want all endpoints unchanged", reachable, droppedAny)
+		}
+	})
+
+	t.Run("keeps endpoints that accept the connection and drops ones that refuse it", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("error starting listener: %v", err)
+		}
+		defer listener.Close()
+		port := int64(listener.Addr().(*net.TCPAddr).Port)
+
+		// 127.0.0.1 has a listener on port; 127.0.0.2 doesn't, so it should be refused and
+		// dropped. Both dial concurrently, exercising filterReachable's per-endpoint
+		// goroutines.
+		probe := []weightedEndpoint{
+			{Endpoint: "127.0.0.1", Cluster: "reachable", Weight: 1},
+			{Endpoint: "127.0.0.2", Cluster: "unreachable", Weight: 1},
+		}
+
+		reachable, droppedAny := filterReachable(probe, port)
+		if !droppedAny {
+			t.Fatalf("filterReachable() dropped nothing, want the unreachable endpoint dropped")
+		}
+		want := []weightedEndpoint{probe[0]}
+		if !sameEndpoints(reachable, want) {
+			t.Errorf("filterReachable() = %+v, want %+v", reachable, want)
+		}
+	})
+}
+
+func sameEndpoints(got, want []weightedEndpoint) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	sortEndpoints := func(eps []weightedEndpoint) []weightedEndpoint {
+		sorted := append([]weightedEndpoint{}, eps...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Endpoint < sorted[j].Endpoint })
+		return sorted
+	}
+	g, w := sortEndpoints(got), sortEndpoints(want)
+	for i := range g {
+		if g[i] != w[i] {
+			return false
+		}
+	}
+	return true
+}